@@ -4,111 +4,72 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"log"
 	"log/slog"
 	"math/rand"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	sloglogstash "github.com/samber/slog-logstash/v2"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	"github.com/uber/jaeger-client-go/config"
+	"go.opentelemetry.io/otel"
+
+	"github.com/HadiShirath/Observability-and-Monitoring-Go/pkg/observability"
 )
 
-const serviceName = "golang_app"
+const (
+	serviceName       = "golang_app"
+	metricsConfigPath = "metrics.yaml"
+)
 
-// initializing opentracing tracer using Jaeger
-func InitTracer(serviceName string) (opentracing.Tracer, io.Closer) {
-	cfg := &config.Configuration{
-		ServiceName: serviceName,
-		Sampler: &config.SamplerConfig{
-			Type:  "const",
-			Param: 1, // push all tracer
-		},
-		Reporter: &config.ReporterConfig{
-			LogSpans:           true,
-			LocalAgentHostPort: "localhost:6831",
-		},
-	}
+func main() {
 
-	tracer, closer, err := cfg.NewTracer(config.Logger(jaeger.StdLogger))
+	tracerProvider, err := observability.NewTracerProvider(context.Background(), observability.TracingConfig{
+		ServiceName:    serviceName,
+		ServiceVersion: "1.0.0",
+		Environment:    "dev",
+		OTLPProtocol:   "grpc",
+		OTLPEndpoint:   "localhost:4317",
+	})
 	if err != nil {
-		slog.Error("failed to start tracer")
+		log.Fatalf("could not start tracer provider: %v", err)
 	}
 
-	opentracing.SetGlobalTracer(tracer)
-	return tracer, closer
-}
+	tracer := tracerProvider.Tracer(serviceName)
 
-var (
-	metricCounter = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: serviceName,
-			Name:      "http_request_count",
-		},
-		[]string{"method", "path", "code"}, // label
+	metricsRegistry := prometheus.NewRegistry()
+	provider := observability.NewPrometheusProvider(
+		observability.Config{ServiceName: serviceName, Namespace: serviceName},
+		metricsRegistry,
+		slog.Default(),
+		tracer,
 	)
 
-	metricGauge = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: serviceName,
-			Name:      "active_users",
-		},
-		[]string{"country_id", "city_id"},
-	)
+	redMetrics := observability.NewREDMetrics(provider)
 
-	// usecase: order processing duration summary
-	metricSummary = prometheus.NewSummary(
-		prometheus.SummaryOpts{
-			Namespace:  serviceName,
-			Name:       "order_processing_duration",
-			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
-		},
+	dynamicMetrics := observability.NewDynamicMetrics(
+		observability.Config{ServiceName: serviceName, Namespace: serviceName},
+		metricsRegistry,
+		slog.Default(),
 	)
-
-	metricsHistogram = prometheus.NewHistogram(
-		prometheus.HistogramOpts{
-			Namespace: serviceName,
-			Name:      "transcation_processing_time",
-			Buckets:   []float64{0.5, 1, 2},
-		},
-	)
-)
-
-func init() {
-	prometheus.MustRegister(metricCounter)
-	prometheus.MustRegister(metricGauge)
-	prometheus.MustRegister(metricSummary)
-	prometheus.MustRegister(metricsHistogram)
-}
-
-func main() {
-
-	tracer, closer := InitTracer(serviceName)
-	defer closer.Close()
-
-	logstashAddr := "localhost:5000"
-	conn, err := net.Dial("tcp", logstashAddr)
-	if err != nil {
-		log.Fatalf("could not connect to Logstash: %v", err)
-	} else {
-		println("connected to logstash successfully")
+	if err := dynamicMetrics.Load(metricsConfigPath); err != nil {
+		log.Fatalf("could not load metrics config: %v", err)
+	}
+	watchCtx, cancelMetricsWatch := context.WithCancel(context.Background())
+	defer cancelMetricsWatch()
+	if err := dynamicMetrics.Watch(watchCtx); err != nil {
+		log.Printf("could not watch metrics config for changes: %v\n", err)
 	}
-	defer conn.Close()
 
-	// Create a new logger with the Logstash handler
-	_ = sloglogstash.Option{Level: slog.LevelDebug, Conn: conn}.NewLogstashHandler()
+	logstashHandler := observability.NewLogstashHandler("localhost:5000", provider, &slog.HandlerOptions{Level: slog.LevelDebug})
 
-	logHandler := slog.NewJSONHandler(conn, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logHandler := observability.NewTraceContextHandler(logstashHandler)
 	logger := slog.New(logHandler)
 	slog.SetDefault(logger)
 
@@ -130,7 +91,7 @@ func main() {
 				return
 			default:
 				// log.Printf("sending metrics at %s\n", time.Now().Format(time.RFC3339Nano))
-				metricGauge.WithLabelValues("ID", "JAK").Add(float64(rand.Intn(100)))
+				dynamicMetrics.Gauge("active_users").With("ID", "Jakarta").Add(float64(rand.Intn(100)))
 				time.Sleep(1 * time.Second)
 			}
 		}
@@ -140,32 +101,34 @@ func main() {
 		Addr: ":1000",
 	}
 
-	http.Handle("/metrics", promhttp.Handler())
-	http.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
-		metricCounter.WithLabelValues("GET", "/ping", "200").Inc()
+	metricsToken := os.Getenv("METRICS_TOKEN")
+	if metricsToken == "" {
+		logger.Warn("METRICS_TOKEN is unset, /sys/metrics is exposed without a token")
+	}
+
+	http.Handle("/metrics", promhttp.HandlerFor(provider.Registry(), promhttp.HandlerOpts{}))
+	http.Handle("/sys/metrics", observability.MetricsHandler(metricsRegistry, "X-Metrics-Token", metricsToken))
+	http.Handle("/ping", observability.InstrumentHandler(redMetrics, "/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`pong`))
-	})
+	})))
 
 	insertToDB := func(ctx context.Context) {
-		span, _ := opentracing.StartSpanFromContext(ctx, "2_inserting_order_data_to_db")
-		defer span.Finish()
+		ctx, span := tracer.Start(ctx, "2_inserting_order_data_to_db")
+		defer span.End()
 
 		time.Sleep(500 * time.Millisecond)
-		logger.Info("inserting to db...")
+		logger.InfoContext(ctx, "inserting to db...")
 	}
 
-	http.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
-		span := tracer.StartSpan("creating order")
-		defer span.Finish()
-
-		// inject opentracing span to context
-		ctx := opentracing.ContextWithSpan(r.Context(), span)
+	http.Handle("/orders", observability.InstrumentHandler(redMetrics, "/orders", observability.TraceServerMiddleware("/orders", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "creating order")
+		defer span.End()
 
 		// 1. executing validation flow
-		subSpan1, _ := opentracing.StartSpanFromContext(ctx, "1_validating_order_data")
+		ctx, subSpan1 := tracer.Start(ctx, "1_validating_order_data")
 		time.Sleep(200 * time.Millisecond)
-		subSpan1.Finish()
+		subSpan1.End()
 
 		// 2. executing DB insertion query
 		insertToDB(ctx)
@@ -198,7 +161,6 @@ func main() {
 		// to simulate successful or failing http status codes
 		if responseData.Data.OrderID%2 == 0 {
 			time.Sleep(100 * time.Millisecond)
-			metricCounter.WithLabelValues("POST", "/orders", "200").Inc()
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			_ = json.NewEncoder(w).Encode(responseData)
@@ -209,8 +171,7 @@ func main() {
 			// logger.Error("failed to connect to inventory service with error: ", err)
 
 			customerID := "123" // taken from http request payload
-			logger.With("customer_id", customerID, "error", err, "product_id", "product-a", "product_category", "electronic").Error("failed to connect to inventory service")
-			metricCounter.WithLabelValues("POST", "/orders", "500").Inc()
+			logger.With("customer_id", customerID, "error", err, "product_id", "product-a", "product_category", "electronic").ErrorContext(ctx, "failed to connect to inventory service")
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusInternalServerError)
 			responseData.Error = Error{
@@ -220,21 +181,20 @@ func main() {
 		}
 
 		duration := time.Since(startTime)
-		metricSummary.Observe(duration.Seconds())
-		metricsHistogram.Observe(duration.Seconds())
-	})
+		dynamicMetrics.Summary("order_processing_duration").With().Observe(duration.Seconds())
+		dynamicMetrics.Histogram("transcation_processing_time").With().Observe(duration.Seconds())
+	}))))
 
-	http.HandleFunc("/internal/orders", func(w http.ResponseWriter, r *http.Request) {
-		span := tracer.StartSpan("creating order")
-		defer span.Finish()
+	http.Handle("/internal/orders", observability.InstrumentHandler(redMetrics, "/internal/orders", observability.TraceServerMiddleware("/internal/orders", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "creating order")
+		defer span.End()
 
 		// simulating httpcall failure to xendit api
 		err := errors.New("payment gateway not responding, with http status code 502")
-		logger.With("tracer_id", "trace-id-a", "request_id", "request-id-sample", "customer_id", "customer-1", "error", err, "product_id", "product-a", "order_id", "order-id-sample").Error("failing validating payment")
-		metricCounter.WithLabelValues("GET", "/internal/orders", "200").Inc()
+		logger.With("request_id", "request-id-sample", "customer_id", "customer-1", "error", err, "product_id", "product-a", "order_id", "order-id-sample").ErrorContext(ctx, "failing validating payment")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"data":{}}`))
-	})
+	}))))
 
 	go func() {
 		err := server.ListenAndServe()
@@ -247,64 +207,86 @@ func main() {
 	<-sigCH
 	log.Print("termination signal received, shutting down...")
 	cancelSimulationJob()
+	cancelMetricsWatch()
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownTimeout := 5 * time.Second
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			shutdownTimeout = d
+		} else {
+			log.Printf("invalid SHUTDOWN_TIMEOUT %q, using default %s\n", v, shutdownTimeout)
+		}
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
 
-	shutdownErr := server.Shutdown(shutdownCtx)
-	if shutdownErr != nil {
-		log.Printf("failled to shutdown http server due to error: %s\n", shutdownErr)
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shutdown http server due to error: %s\n", err)
 	} else {
 		log.Println("http server stopped gracefully")
 	}
 
-	// just to wait all logs from goroutine is also printed, for debugging purpose
-	time.Sleep(2 * time.Second)
-}
-
-type LogstashHandler struct {
-	conn net.Conn
-}
+	// drain metrics, logs, and traces deterministically, bounded by
+	// shutdownCtx instead of a fixed sleep, so termination stays safe
+	// under a Kubernetes terminationGracePeriodSeconds. logstashHandler.Close
+	// actually flushes the buffered frames (not just closes the socket) now
+	// that logstashSink drains on shutdown instead of racing its done channel.
+	subsystems := map[string]func(context.Context) error{
+		"tracer provider":  tracerProvider.Shutdown,
+		"logstash handler": logstashHandler.Close,
+		"pushgateway push": func(ctx context.Context) error {
+			return observability.PushMetrics(ctx, metricsRegistry, serviceName, os.Getenv("PUSHGATEWAY_URL"))
+		},
+	}
 
-func NewLogstashHandler(address string) (*LogstashHandler, error) {
-	conn, err := net.Dial("tcp", address)
-	if err != nil {
-		return nil, err
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for name, drain := range subsystems {
+		wg.Add(1)
+		go func(name string, drain func(context.Context) error) {
+			defer wg.Done()
+			if err := drain(shutdownCtx); err != nil {
+				log.Printf("failed to drain %s: %v\n", name, err)
+				failed.Add(1)
+			}
+		}(name, drain)
 	}
-	return &LogstashHandler{conn: conn}, nil
-}
 
-func (h *LogstashHandler) Handle(ctx context.Context, record slog.Record) error {
-	timestamp := time.Now().Format(time.RFC3339)
-	message := record.Message
-	logLine := timestamp + " " + record.Level.String() + " " + message + "\n"
-	_, err := h.conn.Write([]byte(logLine))
-	return err
-}
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
 
-func (h *LogstashHandler) Enabled(ctx context.Context, level slog.Level) {
-	// return
-}
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		log.Println("timed out waiting for subsystems to drain")
+		os.Exit(1)
+	}
 
-func (h *LogstashHandler) Close() error {
-	return h.conn.Close()
+	if failed.Load() > 0 {
+		os.Exit(1)
+	}
 }
 
 func callInventoryService(ctx context.Context) {
-	span, _ := opentracing.StartSpanFromContext(ctx, "3_http_get_inventory_service_check_stock")
-	defer span.Finish()
-
-	req, _ := http.NewRequest("GET", "http://localhost:2000/checkstock", http.NoBody)
-	client := &http.Client{}
+	tracer := otel.Tracer(serviceName)
+	ctx, span := tracer.Start(ctx, "3_http_get_inventory_service_check_stock")
+	defer span.End()
 
-	carrier := opentracing.HTTPHeadersCarrier(req.Header)
-	opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://localhost:2000/checkstock", http.NoBody)
+	client := &http.Client{Transport: observability.TracingRoundTripper(nil)}
 
+	// the transport injects the W3C traceparent header from req.Context(),
+	// so no manual carrier injection is needed here.
 	res, err := client.Do(req)
 	if err != nil {
 		slog.Error("failed to call inventory service")
+		return
 	}
 	defer res.Body.Close()
 }
 
-//port:1000
\ No newline at end of file
+//port:1000
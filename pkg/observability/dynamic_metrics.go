@@ -0,0 +1,239 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DynamicMetrics loads metric definitions from a metrics.yaml file and
+// re-registers them on change, so operators can add labels or retune
+// histogram buckets without rebuilding the binary. A config_reloads_total
+// counter (labelled result=success|failure) observes reload health.
+type DynamicMetrics struct {
+	registry  *prometheus.Registry
+	namespace string
+	subsystem string
+	logger    *slog.Logger
+
+	reloadsTotal *prometheus.CounterVec
+
+	mu         sync.RWMutex
+	path       string
+	collectors map[string]prometheus.Collector
+	counters   map[string]Counter
+	gauges     map[string]Gauge
+	histograms map[string]Histogram
+	summaries  map[string]Summary
+}
+
+// NewDynamicMetrics builds a DynamicMetrics that registers every metric it
+// loads onto registry, namespaced per cfg.
+func NewDynamicMetrics(cfg Config, registry *prometheus.Registry, logger *slog.Logger) *DynamicMetrics {
+	reloadsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      "config_reloads_total",
+		Help:      "Count of metrics.yaml reload attempts, labelled by result.",
+	}, []string{"result"})
+	registry.MustRegister(reloadsTotal)
+
+	return &DynamicMetrics{
+		registry:     registry,
+		namespace:    cfg.Namespace,
+		subsystem:    cfg.Subsystem,
+		logger:       logger,
+		reloadsTotal: reloadsTotal,
+	}
+}
+
+// Load parses path and replaces the live collectors with ones built from
+// it. Safe to call concurrently with Counter/Gauge/Histogram/Summary
+// lookups and with itself (e.g. from the fsnotify watcher).
+func (d *DynamicMetrics) Load(path string) error {
+	file, err := LoadMetricsFile(path)
+	if err != nil {
+		d.reloadsTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	collectors := make(map[string]prometheus.Collector, len(file.Metrics))
+	counters := make(map[string]Counter)
+	gauges := make(map[string]Gauge)
+	histograms := make(map[string]Histogram)
+	summaries := make(map[string]Summary)
+
+	for _, spec := range file.Metrics {
+		switch spec.Type {
+		case "counter":
+			vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: d.namespace, Subsystem: d.subsystem, Name: spec.Name, Help: spec.Help,
+			}, spec.Labels)
+			collectors[spec.Name] = vec
+			counters[spec.Name] = &prometheusCounter{vec: vec}
+
+		case "gauge":
+			vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: d.namespace, Subsystem: d.subsystem, Name: spec.Name, Help: spec.Help,
+			}, spec.Labels)
+			collectors[spec.Name] = vec
+			gauges[spec.Name] = &prometheusGauge{vec: vec}
+
+		case "histogram":
+			vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: d.namespace, Subsystem: d.subsystem, Name: spec.Name, Help: spec.Help, Buckets: spec.Buckets,
+			}, spec.Labels)
+			collectors[spec.Name] = vec
+			histograms[spec.Name] = &prometheusHistogram{vec: vec}
+
+		case "summary":
+			vec := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+				Namespace: d.namespace, Subsystem: d.subsystem, Name: spec.Name, Help: spec.Help, Objectives: spec.Objectives,
+			}, spec.Labels)
+			collectors[spec.Name] = vec
+			summaries[spec.Name] = &prometheusSummary{vec: vec}
+
+		default:
+			d.reloadsTotal.WithLabelValues("failure").Inc()
+			return fmt.Errorf("observability: metrics config %s: unknown metric type %q for %q", path, spec.Type, spec.Name)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, collector := range d.collectors {
+		d.registry.Unregister(collector)
+	}
+
+	registered := make([]prometheus.Collector, 0, len(collectors))
+	for name, vec := range collectors {
+		if err := d.registry.Register(vec); err != nil {
+			for _, c := range registered {
+				d.registry.Unregister(c)
+			}
+			for _, c := range d.collectors {
+				d.registry.Register(c)
+			}
+			d.reloadsTotal.WithLabelValues("failure").Inc()
+			return fmt.Errorf("observability: metrics config %s: registering %q: %w", path, name, err)
+		}
+		registered = append(registered, vec)
+	}
+
+	d.path = path
+	d.collectors = collectors
+	d.counters = counters
+	d.gauges = gauges
+	d.histograms = histograms
+	d.summaries = summaries
+
+	d.reloadsTotal.WithLabelValues("success").Inc()
+	return nil
+}
+
+// Counter returns the counter declared under name in metrics.yaml, or a
+// no-op Counter if no such metric was loaded (e.g. a reload dropped it) so
+// callers can chain straight into With(...).Add(...) without a nil check
+// that would otherwise panic a live request path.
+func (d *DynamicMetrics) Counter(name string) Counter {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if c, ok := d.counters[name]; ok {
+		return c
+	}
+	return noopCounter{}
+}
+
+// Gauge returns the gauge declared under name in metrics.yaml, or a no-op
+// Gauge if no such metric was loaded (e.g. a reload dropped it) so callers
+// can chain straight into With(...).Set(...)/Add(...) without a nil check
+// that would otherwise panic a live request path.
+func (d *DynamicMetrics) Gauge(name string) Gauge {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if g, ok := d.gauges[name]; ok {
+		return g
+	}
+	return noopGauge{}
+}
+
+// Histogram returns the histogram declared under name in metrics.yaml, or
+// a no-op Histogram if no such metric was loaded (e.g. a reload dropped
+// it) so callers can chain straight into With(...).Observe(...) without a
+// nil check that would otherwise panic a live request path.
+func (d *DynamicMetrics) Histogram(name string) Histogram {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if h, ok := d.histograms[name]; ok {
+		return h
+	}
+	return noopHistogram{}
+}
+
+// Summary returns the summary declared under name in metrics.yaml, or a
+// no-op Summary if no such metric was loaded (e.g. a reload dropped it) so
+// callers can chain straight into With(...).Observe(...) without a nil
+// check that would otherwise panic a live request path.
+func (d *DynamicMetrics) Summary(name string) Summary {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if s, ok := d.summaries[name]; ok {
+		return s
+	}
+	return noopSummary{}
+}
+
+// Watch reloads the config whenever the file at d.path changes, until ctx
+// is cancelled. Load must be called once before Watch.
+func (d *DynamicMetrics) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("observability: starting metrics config watcher: %w", err)
+	}
+
+	// Watch the containing directory rather than the file itself: editors
+	// commonly replace the file (rename over it) rather than write in
+	// place, which an fsnotify watch on the file alone would miss.
+	if err := watcher.Add(filepath.Dir(d.path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("observability: watching %s: %w", filepath.Dir(d.path), err)
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(d.path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if err := d.Load(d.path); err != nil {
+					d.logger.Error("failed to reload metrics config", "path", d.path, "error", err)
+				} else {
+					d.logger.Info("reloaded metrics config", "path", d.path)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				d.logger.Error("metrics config watcher error", "error", err)
+			}
+		}
+	}()
+
+	return nil
+}
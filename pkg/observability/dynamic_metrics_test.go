@@ -0,0 +1,111 @@
+package observability
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func writeMetricsYAML(t *testing.T, dir, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, "metrics.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing metrics config: %v", err)
+	}
+	return path
+}
+
+func TestDynamicMetricsLoadReplacesCollectors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMetricsYAML(t, dir, `
+metrics:
+  - name: active_users
+    type: gauge
+    help: active users
+    labels: [country_id]
+`)
+
+	registry := prometheus.NewRegistry()
+	dm := NewDynamicMetrics(Config{Namespace: "test"}, registry, nil)
+	if err := dm.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	dm.Gauge("active_users").With("ID").Set(3)
+
+	writeMetricsYAML(t, dir, `
+metrics:
+  - name: active_sessions
+    type: gauge
+    help: active sessions
+    labels: [country_id]
+`)
+
+	if err := dm.Load(path); err != nil {
+		t.Fatalf("second Load() error = %v", err)
+	}
+
+	if dm.Gauge("active_users") != (noopGauge{}) {
+		t.Error("active_users should fall back to a no-op gauge after a reload that drops it")
+	}
+
+	dm.Gauge("active_sessions").With("ID").Set(5)
+}
+
+func TestDynamicMetricsLoadRollsBackOnConflict(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMetricsYAML(t, dir, `
+metrics:
+  - name: active_users
+    type: gauge
+    help: active users
+    labels: [country_id]
+`)
+
+	registry := prometheus.NewRegistry()
+	dm := NewDynamicMetrics(Config{Namespace: "test"}, registry, nil)
+	if err := dm.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Pre-register a collider under the namespaced name the next reload
+	// would also try to register, so the reload's Register call fails.
+	collider := prometheus.NewGauge(prometheus.GaugeOpts{Namespace: "test", Name: "active_sessions"})
+	if err := registry.Register(collider); err != nil {
+		t.Fatalf("registering collider: %v", err)
+	}
+
+	writeMetricsYAML(t, dir, `
+metrics:
+  - name: active_sessions
+    type: gauge
+    help: active sessions
+    labels: [country_id]
+`)
+
+	if err := dm.Load(path); err == nil {
+		t.Fatal("Load() error = nil, want a conflict error")
+	}
+
+	// The original active_users gauge should still be live and registered.
+	dm.Gauge("active_users").With("ID").Set(1)
+	if dm.Gauge("active_sessions") != (noopGauge{}) {
+		t.Error("active_sessions should not have replaced active_users after a failed reload")
+	}
+}
+
+func TestDynamicMetricsUnknownTypeFails(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMetricsYAML(t, dir, `
+metrics:
+  - name: mystery
+    type: bogus
+`)
+
+	dm := NewDynamicMetrics(Config{Namespace: "test"}, prometheus.NewRegistry(), nil)
+	if err := dm.Load(path); err == nil {
+		t.Fatal("Load() error = nil, want an error for an unknown metric type")
+	}
+}
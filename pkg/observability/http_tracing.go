@@ -0,0 +1,25 @@
+package observability
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// TraceServerMiddleware wraps h so that every incoming request starts a
+// span (named route) and propagates the extracted trace context via
+// r.Context(), replacing manual opentracing.ContextWithSpan plumbing.
+func TraceServerMiddleware(route string, h http.Handler) http.Handler {
+	return otelhttp.NewHandler(h, route)
+}
+
+// TracingRoundTripper wraps base so that outbound requests inject the W3C
+// traceparent header from the request's span context, replacing the manual
+// opentracing.HTTPHeadersCarrier injection. Pass nil to wrap
+// http.DefaultTransport.
+func TracingRoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -0,0 +1,267 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"time"
+)
+
+const (
+	logstashBufferSize  = 1024
+	logstashDialTimeout = 5 * time.Second
+	logstashMinBackoff  = 500 * time.Millisecond
+	logstashMaxBackoff  = 30 * time.Second
+)
+
+// logstashSink owns the TCP connection and the ring buffer shared by every
+// LogstashHandler derived via WithAttrs/WithGroup, so they all drain
+// through the same background flusher instead of racing on their own
+// sockets.
+type logstashSink struct {
+	addr       string
+	buf        chan []byte
+	dropped    Counter
+	reconnects Counter
+	done       chan struct{}
+	stopped    chan struct{}
+
+	// drainDeadline is set by close before done is closed, and read by
+	// run after observing done. The channel close happens-before that
+	// read, so no further synchronization is needed.
+	drainDeadline time.Time
+}
+
+func newLogstashSink(addr string, provider Provider) *logstashSink {
+	sink := &logstashSink{
+		addr:       addr,
+		buf:        make(chan []byte, logstashBufferSize),
+		dropped:    provider.NewCounter("logstash_dropped_total", nil).With(),
+		reconnects: provider.NewCounter("logstash_reconnects_total", nil).With(),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+// enqueue buffers frame for delivery without ever blocking the caller. If
+// the buffer is full (a slow or dead Logstash), the frame is dropped and
+// logstash_dropped_total is incremented rather than backing up request
+// handlers.
+func (s *logstashSink) enqueue(frame []byte) {
+	select {
+	case s.buf <- frame:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run is the background flusher: it dials Logstash, reconnecting with
+// exponential backoff on failure, and writes every buffered frame to the
+// current connection.
+func (s *logstashSink) run() {
+	defer close(s.stopped)
+
+	var conn net.Conn
+	backoff := logstashMinBackoff
+
+	for {
+		if conn == nil {
+			var err error
+			conn, err = net.DialTimeout("tcp", s.addr, logstashDialTimeout)
+			if err != nil {
+				select {
+				case <-s.done:
+					return
+				case <-time.After(backoff):
+				}
+				backoff = nextBackoff(backoff)
+				continue
+			}
+			backoff = logstashMinBackoff
+		}
+
+		select {
+		case <-s.done:
+			s.drain(conn)
+			conn.Close()
+			return
+		case frame := <-s.buf:
+			if _, err := conn.Write(frame); err != nil {
+				conn.Close()
+				conn = nil
+				s.reconnects.Add(1)
+			}
+		}
+	}
+}
+
+// drain flushes every frame already sitting in s.buf to conn before run
+// returns, bounded by drainDeadline (if any), so a Close call actually
+// delivers what was queued instead of discarding it.
+func (s *logstashSink) drain(conn net.Conn) {
+	var deadline <-chan time.Time
+	if !s.drainDeadline.IsZero() {
+		timer := time.NewTimer(time.Until(s.drainDeadline))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		select {
+		case frame := <-s.buf:
+			if _, err := conn.Write(frame); err != nil {
+				return
+			}
+		case <-deadline:
+			return
+		default:
+			return
+		}
+	}
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > logstashMaxBackoff {
+		return logstashMaxBackoff
+	}
+	return next
+}
+
+// close stops the flusher, best-effort draining whatever is still
+// buffered until ctx is done.
+func (s *logstashSink) close(ctx context.Context) error {
+	if dl, ok := ctx.Deadline(); ok {
+		s.drainDeadline = dl
+	}
+	close(s.done)
+	select {
+	case <-s.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// groupOrAttrs records either a WithGroup or a WithAttrs call, in the
+// order they were made, so Handle can replay them to build each record's
+// nested JSON object.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// LogstashHandler is a slog.Handler that ships JSON-lines records to
+// Logstash over TCP. Unlike net.Conn written to directly, it never blocks
+// or kills the process when Logstash is slow or unreachable: records are
+// buffered and delivered by a background goroutine that reconnects with
+// exponential backoff. Records are dropped, and counted in
+// logstash_dropped_total, when the buffer is full. A record whose write
+// fails on a broken connection, and any record still buffered when Close
+// is called while no connection is up, is discarded without being counted.
+type LogstashHandler struct {
+	sink  *logstashSink
+	level slog.Leveler
+	goas  []groupOrAttrs
+}
+
+// NewLogstashHandler dials addr in the background and returns a handler
+// that ships every accepted record to it as a JSON-lines frame. provider
+// supplies the logstash_dropped_total/logstash_reconnects_total counters.
+func NewLogstashHandler(addr string, provider Provider, opts *slog.HandlerOptions) *LogstashHandler {
+	if opts == nil {
+		opts = &slog.HandlerOptions{}
+	}
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+
+	return &LogstashHandler{
+		sink:  newLogstashSink(addr, provider),
+		level: level,
+	}
+}
+
+func (h *LogstashHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *LogstashHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := map[string]any{
+		"@timestamp": record.Time.Format(time.RFC3339Nano),
+		"level":      record.Level.String(),
+		"message":    record.Message,
+	}
+
+	current := fields
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			nested := map[string]any{}
+			current[goa.group] = nested
+			current = nested
+			continue
+		}
+		for _, a := range goa.attrs {
+			addAttr(current, a)
+		}
+	}
+
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(current, a)
+		return true
+	})
+
+	frame, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	frame = append(frame, '\n')
+
+	h.sink.enqueue(frame)
+	return nil
+}
+
+func (h *LogstashHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.goas = append(append([]groupOrAttrs{}, h.goas...), groupOrAttrs{attrs: attrs})
+	return &clone
+}
+
+func (h *LogstashHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.goas = append(append([]groupOrAttrs{}, h.goas...), groupOrAttrs{group: name})
+	return &clone
+}
+
+// Close stops the background flusher, best-effort draining whatever is
+// still buffered until ctx is done.
+func (h *LogstashHandler) Close(ctx context.Context) error {
+	return h.sink.close(ctx)
+}
+
+func addAttr(dst map[string]any, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		nested := map[string]any{}
+		for _, ga := range a.Value.Group() {
+			addAttr(nested, ga)
+		}
+		dst[a.Key] = nested
+		return
+	}
+	if err, ok := a.Value.Any().(error); ok {
+		dst[a.Key] = err.Error()
+		return
+	}
+	dst[a.Key] = a.Value.Any()
+}
@@ -0,0 +1,72 @@
+package observability
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+)
+
+func slogRecord(message string) slog.Record {
+	return slog.NewRecord(time.Now(), slog.LevelInfo, message, 0)
+}
+
+func TestLogstashHandlerFlushesBufferOnClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 8)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+
+	provider := NewNoopProvider()
+	handler := NewLogstashHandler(listener.Addr().String(), provider, nil)
+
+	// Give the sink a moment to dial before we enqueue, so the frames land
+	// on the buffer the connected flusher drains rather than racing dial.
+	time.Sleep(100 * time.Millisecond)
+
+	const messages = 5
+	for i := 0; i < messages; i++ {
+		record := slogRecord("queued message")
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := handler.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for i := 0; i < messages; i++ {
+		select {
+		case line := <-received:
+			var payload map[string]any
+			if err := json.Unmarshal([]byte(line), &payload); err != nil {
+				t.Fatalf("unmarshaling flushed frame: %v", err)
+			}
+			if payload["message"] != "queued message" {
+				t.Errorf("message = %v, want %q", payload["message"], "queued message")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("only received %d/%d frames after Close drained the buffer", i, messages)
+		}
+	}
+}
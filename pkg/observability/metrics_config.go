@@ -0,0 +1,40 @@
+package observability
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetricSpec declares one metric line of metrics.yaml: its name, type,
+// help text, labels, and (for histograms/summaries) buckets or
+// objectives.
+type MetricSpec struct {
+	Name       string              `yaml:"name"`
+	Type       string              `yaml:"type"` // counter, gauge, histogram, summary
+	Help       string              `yaml:"help"`
+	Labels     []string            `yaml:"labels"`
+	Buckets    []float64           `yaml:"buckets"`
+	Objectives map[float64]float64 `yaml:"objectives"`
+}
+
+// MetricsFile is the root document of metrics.yaml.
+type MetricsFile struct {
+	Metrics []MetricSpec `yaml:"metrics"`
+}
+
+// LoadMetricsFile parses a metrics.yaml document from path.
+func LoadMetricsFile(path string) (*MetricsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("observability: reading metrics config %s: %w", path, err)
+	}
+
+	var file MetricsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("observability: parsing metrics config %s: %w", path, err)
+	}
+
+	return &file, nil
+}
@@ -0,0 +1,171 @@
+package observability
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// MetricSample is one labelled observation of a metric, rendered for the
+// format=json response of MetricsHandler. Name matches the family name for
+// a Counter/Gauge/Untyped sample; a Histogram/Summary expands into several
+// samples (buckets/quantiles plus _sum and _count), each carrying the
+// conventional Prometheus suffix in Name, the same way the text exposition
+// format would.
+type MetricSample struct {
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Value     float64           `json:"value"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// MetricFamily is one metric family (a name shared by every sample
+// produced from the same Counter/Gauge/.../Vec), rendered for the
+// format=json response of MetricsHandler.
+type MetricFamily struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	Help    string         `json:"help"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// MetricsHandler exposes registry at both format=json (an array of
+// MetricFamily, for consumers that don't parse the Prometheus text
+// format) and format=prometheus (the usual promhttp text exposition),
+// gated behind a token header so it's safe to mount on the main service
+// port. An empty token disables the check.
+func MetricsHandler(registry *prometheus.Registry, tokenHeader, token string) http.Handler {
+	prometheusHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get(tokenHeader) != token {
+			http.Error(w, "invalid or missing metrics token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Query().Get("format") == "prometheus" {
+			prometheusHandler.ServeHTTP(w, r)
+			return
+		}
+
+		families, err := registry.Gather()
+		if err != nil {
+			http.Error(w, "failed to gather metrics", http.StatusInternalServerError)
+			return
+		}
+
+		now := time.Now()
+		result := make([]MetricFamily, 0, len(families))
+		for _, family := range families {
+			result = append(result, toMetricFamily(family, now))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+func toMetricFamily(family *dto.MetricFamily, now time.Time) MetricFamily {
+	name := family.GetName()
+	out := MetricFamily{
+		Name: name,
+		Type: family.GetType().String(),
+		Help: family.GetHelp(),
+	}
+
+	for _, m := range family.GetMetric() {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+
+		timestamp := now
+		if m.GetTimestampMs() != 0 {
+			timestamp = time.UnixMilli(m.GetTimestampMs())
+		}
+
+		switch {
+		case m.Counter != nil:
+			out.Samples = append(out.Samples, MetricSample{Name: name, Labels: labels, Value: m.GetCounter().GetValue(), Timestamp: timestamp})
+		case m.Gauge != nil:
+			out.Samples = append(out.Samples, MetricSample{Name: name, Labels: labels, Value: m.GetGauge().GetValue(), Timestamp: timestamp})
+		case m.Summary != nil:
+			out.Samples = append(out.Samples, summarySamples(name, m.GetSummary(), labels, timestamp)...)
+		case m.Histogram != nil:
+			out.Samples = append(out.Samples, histogramSamples(name, m.GetHistogram(), labels, timestamp)...)
+		case m.Untyped != nil:
+			out.Samples = append(out.Samples, MetricSample{Name: name, Labels: labels, Value: m.GetUntyped().GetValue(), Timestamp: timestamp})
+		}
+	}
+
+	return out
+}
+
+// summarySamples expands a summary metric into one sample per quantile,
+// plus the trailing _sum/_count samples, matching the series the
+// Prometheus text exposition format would produce.
+func summarySamples(name string, s *dto.Summary, labels map[string]string, timestamp time.Time) []MetricSample {
+	samples := make([]MetricSample, 0, len(s.GetQuantile())+2)
+	for _, q := range s.GetQuantile() {
+		samples = append(samples, MetricSample{
+			Name:      name,
+			Labels:    withLabel(labels, "quantile", formatFloat(q.GetQuantile())),
+			Value:     q.GetValue(),
+			Timestamp: timestamp,
+		})
+	}
+	return append(samples,
+		MetricSample{Name: name + "_sum", Labels: labels, Value: s.GetSampleSum(), Timestamp: timestamp},
+		MetricSample{Name: name + "_count", Labels: labels, Value: float64(s.GetSampleCount()), Timestamp: timestamp},
+	)
+}
+
+// histogramSamples expands a histogram metric into one sample per bucket,
+// plus the trailing _sum/_count samples, matching the series the
+// Prometheus text exposition format would produce.
+func histogramSamples(name string, h *dto.Histogram, labels map[string]string, timestamp time.Time) []MetricSample {
+	samples := make([]MetricSample, 0, len(h.GetBucket())+3)
+	for _, b := range h.GetBucket() {
+		samples = append(samples, MetricSample{
+			Name:      name + "_bucket",
+			Labels:    withLabel(labels, "le", formatFloat(b.GetUpperBound())),
+			Value:     float64(b.GetCumulativeCount()),
+			Timestamp: timestamp,
+		})
+	}
+	// The gathered buckets omit the implicit +Inf bucket, which the text
+	// exposition format always writes out with the total sample count.
+	if buckets := h.GetBucket(); len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].GetUpperBound(), +1) {
+		samples = append(samples, MetricSample{
+			Name:      name + "_bucket",
+			Labels:    withLabel(labels, "le", "+Inf"),
+			Value:     float64(h.GetSampleCount()),
+			Timestamp: timestamp,
+		})
+	}
+	return append(samples,
+		MetricSample{Name: name + "_sum", Labels: labels, Value: h.GetSampleSum(), Timestamp: timestamp},
+		MetricSample{Name: name + "_count", Labels: labels, Value: float64(h.GetSampleCount()), Timestamp: timestamp},
+	)
+}
+
+// withLabel returns a copy of labels with key=value added, leaving the
+// original map (shared with sibling samples) untouched.
+func withLabel(labels map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
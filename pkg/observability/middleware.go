@@ -0,0 +1,79 @@
+package observability
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// REDMetrics holds the RED (rate, errors, duration) instrumentation shared
+// by every route wrapped with InstrumentHandler. Build it once via
+// NewREDMetrics and reuse it across routes: each of its counters/histograms
+// is registered under a fixed name. A repeated NewREDMetrics call on the
+// same PrometheusProvider gets back the already-registered handles, but a
+// second Provider on the same registry panics on the duplicate names.
+type REDMetrics struct {
+	requestsTotal    Counter
+	requestsInFlight Gauge
+	requestDuration  Histogram
+	responseSize     Histogram
+}
+
+// NewREDMetrics registers the RED collectors on provider. Call it once at
+// startup and pass the result to every InstrumentHandler call.
+func NewREDMetrics(provider Provider) *REDMetrics {
+	labels := []string{"method", "route", "code"}
+	return &REDMetrics{
+		requestsTotal:    provider.NewCounter("http_requests_total", labels),
+		requestsInFlight: provider.NewGauge("http_requests_in_flight", []string{"method", "route"}),
+		requestDuration:  provider.NewHistogram("http_request_duration_seconds", labels, []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}),
+		responseSize:     provider.NewHistogram("http_response_size_bytes", labels, []float64{100, 1000, 10000, 100000}),
+	}
+}
+
+// InstrumentHandler wraps h so that every request through it automatically
+// records request count, in-flight requests, request duration, and
+// response size, labelled by method, route name, and status code. route is
+// the route template (e.g. "/orders"), not the raw request path, so
+// cardinality stays bounded regardless of path parameters. metrics must be
+// shared (via NewREDMetrics) across every route instrumented for the same
+// Provider.
+func InstrumentHandler(metrics *REDMetrics, route string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight := metrics.requestsInFlight.With(r.Method, route)
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		start := time.Now()
+		delegate := &statusCapturingWriter{ResponseWriter: w, status: http.StatusOK}
+
+		h.ServeHTTP(delegate, r)
+
+		duration := time.Since(start).Seconds()
+		code := strconv.Itoa(delegate.status)
+
+		metrics.requestsTotal.With(r.Method, route, code).Add(1)
+		metrics.requestDuration.With(r.Method, route, code).Observe(duration)
+		metrics.responseSize.With(r.Method, route, code).Observe(float64(delegate.bytesWritten))
+	})
+}
+
+// statusCapturingWriter delegates to an http.ResponseWriter while recording
+// the status code and byte count written, so InstrumentHandler can observe
+// them after the handler returns without the handler cooperating.
+type statusCapturingWriter struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int
+}
+
+func (w *statusCapturingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusCapturingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytesWritten += n
+	return n, err
+}
@@ -0,0 +1,106 @@
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstrumentHandlerCapturesStatusAndSize(t *testing.T) {
+	provider := NewNoopProvider()
+	metrics := NewREDMetrics(provider)
+
+	var gotStatus, gotSize int
+	metrics.requestsTotal = recordingCounter(func(labelValues ...string) {
+		if len(labelValues) != 3 {
+			t.Fatalf("requestsTotal.With got %d label values, want 3", len(labelValues))
+		}
+	})
+	metrics.requestDuration = recordingHistogram(func(labelValues ...string) {})
+	var observedSize float64
+	metrics.responseSize = observingHistogram{
+		onWith: func(labelValues ...string) {
+			if labelValues[2] != "201" {
+				t.Errorf("responseSize code label = %q, want %q", labelValues[2], "201")
+			}
+		},
+		observed: &observedSize,
+	}
+
+	body := []byte("hello")
+	handler := InstrumentHandler(metrics, "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/widgets", nil))
+
+	gotStatus = rec.Code
+	gotSize = rec.Body.Len()
+
+	if gotStatus != http.StatusCreated {
+		t.Errorf("recorded status = %d, want %d", gotStatus, http.StatusCreated)
+	}
+	if gotSize != len(body) {
+		t.Errorf("response body length = %d, want %d", gotSize, len(body))
+	}
+	if observedSize != float64(len(body)) {
+		t.Errorf("observed response size = %v, want %d", observedSize, len(body))
+	}
+}
+
+func TestInstrumentHandlerDefaultsStatusWhenUnset(t *testing.T) {
+	provider := NewNoopProvider()
+	metrics := NewREDMetrics(provider)
+
+	var observedCode string
+	metrics.requestsTotal = recordingCounter(func(labelValues ...string) {
+		observedCode = labelValues[2]
+	})
+
+	handler := InstrumentHandler(metrics, "/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if observedCode != "200" {
+		t.Errorf("code label = %q, want %q when WriteHeader is never called", observedCode, "200")
+	}
+}
+
+// recordingCounter/recordingHistogram let a test observe the label values
+// InstrumentHandler passes to With without depending on a real Provider.
+
+type recordingCounter func(labelValues ...string)
+
+func (f recordingCounter) With(labelValues ...string) Counter {
+	f(labelValues...)
+	return noopCounter{}
+}
+
+func (f recordingCounter) Add(delta float64) {}
+
+type recordingHistogram func(labelValues ...string)
+
+func (f recordingHistogram) With(labelValues ...string) Histogram {
+	f(labelValues...)
+	return noopHistogram{}
+}
+
+func (f recordingHistogram) Observe(value float64) {}
+
+// observingHistogram passes With's label values to onWith and stores the
+// last value observed through it in *observed.
+type observingHistogram struct {
+	onWith   func(labelValues ...string)
+	observed *float64
+}
+
+func (h observingHistogram) With(labelValues ...string) Histogram {
+	h.onWith(labelValues...)
+	return h
+}
+
+func (h observingHistogram) Observe(value float64) { *h.observed = value }
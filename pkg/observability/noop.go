@@ -0,0 +1,66 @@
+package observability
+
+import (
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// NoopProvider is a Provider that discards every metric and log record and
+// hands out a no-op OpenTelemetry tracer. It is meant for unit tests that
+// need a Provider to satisfy a handler's constructor without a live
+// Prometheus registry or tracer backend.
+type NoopProvider struct{}
+
+// NewNoopProvider returns a Provider whose metric handles and logger are
+// no-ops.
+func NewNoopProvider() *NoopProvider {
+	return &NoopProvider{}
+}
+
+func (NoopProvider) NewCounter(name string, labels []string) Counter {
+	return noopCounter{}
+}
+
+func (NoopProvider) NewGauge(name string, labels []string) Gauge {
+	return noopGauge{}
+}
+
+func (NoopProvider) NewHistogram(name string, labels []string, buckets []float64) Histogram {
+	return noopHistogram{}
+}
+
+func (NoopProvider) NewSummary(name string, labels []string, objectives map[float64]float64) Summary {
+	return noopSummary{}
+}
+
+func (NoopProvider) Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func (NoopProvider) Tracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer("noop")
+}
+
+type noopCounter struct{}
+
+func (noopCounter) With(labelValues ...string) Counter { return noopCounter{} }
+func (noopCounter) Add(delta float64)                  {}
+
+type noopGauge struct{}
+
+func (noopGauge) With(labelValues ...string) Gauge { return noopGauge{} }
+func (noopGauge) Set(value float64)                {}
+func (noopGauge) Add(delta float64)                {}
+
+type noopHistogram struct{}
+
+func (noopHistogram) With(labelValues ...string) Histogram { return noopHistogram{} }
+func (noopHistogram) Observe(value float64)                {}
+
+type noopSummary struct{}
+
+func (noopSummary) With(labelValues ...string) Summary { return noopSummary{} }
+func (noopSummary) Observe(value float64)              {}
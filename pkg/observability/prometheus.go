@@ -0,0 +1,189 @@
+package observability
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PrometheusProvider is the Provider implementation backed by a
+// prometheus.Registry. It owns the registry and applies a consistent
+// namespace/subsystem to every metric it creates, caching the handle
+// returned for each name so a second NewX call with the same name returns
+// the existing metric instead of panicking on a duplicate registration.
+type PrometheusProvider struct {
+	cfg      Config
+	registry *prometheus.Registry
+	logger   *slog.Logger
+	tracer   trace.Tracer
+
+	mu         sync.Mutex
+	counters   map[string]Counter
+	gauges     map[string]Gauge
+	histograms map[string]Histogram
+	summaries  map[string]Summary
+}
+
+// NewPrometheusProvider builds a Provider that registers every metric it
+// creates onto registry, namespaced per cfg.
+func NewPrometheusProvider(cfg Config, registry *prometheus.Registry, logger *slog.Logger, tracer trace.Tracer) *PrometheusProvider {
+	return &PrometheusProvider{
+		cfg:        cfg,
+		registry:   registry,
+		logger:     logger,
+		tracer:     tracer,
+		counters:   make(map[string]Counter),
+		gauges:     make(map[string]Gauge),
+		histograms: make(map[string]Histogram),
+		summaries:  make(map[string]Summary),
+	}
+}
+
+// Registry returns the underlying prometheus.Registry, e.g. to mount
+// promhttp.HandlerFor in main.
+func (p *PrometheusProvider) Registry() *prometheus.Registry {
+	return p.registry
+}
+
+func (p *PrometheusProvider) NewCounter(name string, labels []string) Counter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.counters[name]; ok {
+		return c
+	}
+
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: p.cfg.Namespace,
+		Subsystem: p.cfg.Subsystem,
+		Name:      name,
+	}, labels)
+	p.registry.MustRegister(vec)
+	c := &prometheusCounter{vec: vec}
+	p.counters[name] = c
+	return c
+}
+
+func (p *PrometheusProvider) NewGauge(name string, labels []string) Gauge {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if g, ok := p.gauges[name]; ok {
+		return g
+	}
+
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: p.cfg.Namespace,
+		Subsystem: p.cfg.Subsystem,
+		Name:      name,
+	}, labels)
+	p.registry.MustRegister(vec)
+	g := &prometheusGauge{vec: vec}
+	p.gauges[name] = g
+	return g
+}
+
+func (p *PrometheusProvider) NewHistogram(name string, labels []string, buckets []float64) Histogram {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if h, ok := p.histograms[name]; ok {
+		return h
+	}
+
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: p.cfg.Namespace,
+		Subsystem: p.cfg.Subsystem,
+		Name:      name,
+		Buckets:   buckets,
+	}, labels)
+	p.registry.MustRegister(vec)
+	h := &prometheusHistogram{vec: vec}
+	p.histograms[name] = h
+	return h
+}
+
+func (p *PrometheusProvider) NewSummary(name string, labels []string, objectives map[float64]float64) Summary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.summaries[name]; ok {
+		return s
+	}
+
+	vec := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace:  p.cfg.Namespace,
+		Subsystem:  p.cfg.Subsystem,
+		Name:       name,
+		Objectives: objectives,
+	}, labels)
+	p.registry.MustRegister(vec)
+	s := &prometheusSummary{vec: vec}
+	p.summaries[name] = s
+	return s
+}
+
+func (p *PrometheusProvider) Logger() *slog.Logger {
+	return p.logger
+}
+
+func (p *PrometheusProvider) Tracer() trace.Tracer {
+	return p.tracer
+}
+
+// prometheusCounter adapts a *prometheus.CounterVec to Counter, currying
+// label values via With the same way prometheus.CounterVec.WithLabelValues
+// does.
+type prometheusCounter struct {
+	vec    *prometheus.CounterVec
+	labels []string
+}
+
+func (c *prometheusCounter) With(labelValues ...string) Counter {
+	return &prometheusCounter{vec: c.vec, labels: labelValues}
+}
+
+func (c *prometheusCounter) Add(delta float64) {
+	c.vec.WithLabelValues(c.labels...).Add(delta)
+}
+
+type prometheusGauge struct {
+	vec    *prometheus.GaugeVec
+	labels []string
+}
+
+func (g *prometheusGauge) With(labelValues ...string) Gauge {
+	return &prometheusGauge{vec: g.vec, labels: labelValues}
+}
+
+func (g *prometheusGauge) Set(value float64) {
+	g.vec.WithLabelValues(g.labels...).Set(value)
+}
+
+func (g *prometheusGauge) Add(delta float64) {
+	g.vec.WithLabelValues(g.labels...).Add(delta)
+}
+
+type prometheusHistogram struct {
+	vec    *prometheus.HistogramVec
+	labels []string
+}
+
+func (h *prometheusHistogram) With(labelValues ...string) Histogram {
+	return &prometheusHistogram{vec: h.vec, labels: labelValues}
+}
+
+func (h *prometheusHistogram) Observe(value float64) {
+	h.vec.WithLabelValues(h.labels...).Observe(value)
+}
+
+type prometheusSummary struct {
+	vec    *prometheus.SummaryVec
+	labels []string
+}
+
+func (s *prometheusSummary) With(labelValues ...string) Summary {
+	return &prometheusSummary{vec: s.vec, labels: labelValues}
+}
+
+func (s *prometheusSummary) Observe(value float64) {
+	s.vec.WithLabelValues(s.labels...).Observe(value)
+}
@@ -0,0 +1,72 @@
+// Package observability centralizes metrics, logging, and tracing setup so
+// that individual services request typed instrumentation handles from a
+// Provider instead of wiring Prometheus/logging/tracing libraries directly
+// into main.
+package observability
+
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config carries the identifying labels applied to every metric emitted by
+// a Provider.
+type Config struct {
+	// ServiceName identifies the service for tracing and logging.
+	ServiceName string
+	// Namespace is the Prometheus namespace prefixed to every metric name.
+	Namespace string
+	// Subsystem is the Prometheus subsystem prefixed to every metric name,
+	// after the namespace. Optional.
+	Subsystem string
+}
+
+// Counter is a metric that accumulates monotonically, e.g. request counts.
+type Counter interface {
+	With(labelValues ...string) Counter
+	Add(delta float64)
+}
+
+// Gauge is a metric that can move up or down, e.g. in-flight requests.
+type Gauge interface {
+	With(labelValues ...string) Gauge
+	Set(value float64)
+	Add(delta float64)
+}
+
+// Histogram observes a distribution of values into fixed buckets, e.g.
+// request duration.
+type Histogram interface {
+	With(labelValues ...string) Histogram
+	Observe(value float64)
+}
+
+// Summary observes a distribution of values as streaming quantiles, e.g.
+// order processing duration.
+type Summary interface {
+	With(labelValues ...string) Summary
+	Observe(value float64)
+}
+
+// Provider is the single entry point handlers use to obtain metric handles
+// and the shared logger/tracer, without touching prometheus.MustRegister or
+// tracer configuration directly.
+type Provider interface {
+	// NewCounter returns the counter registered under name, creating and
+	// registering it on first use.
+	NewCounter(name string, labels []string) Counter
+	// NewGauge returns the gauge registered under name, creating and
+	// registering it on first use.
+	NewGauge(name string, labels []string) Gauge
+	// NewHistogram returns the histogram registered under name, creating
+	// and registering it on first use.
+	NewHistogram(name string, labels []string, buckets []float64) Histogram
+	// NewSummary returns the summary registered under name, creating and
+	// registering it on first use.
+	NewSummary(name string, labels []string, objectives map[float64]float64) Summary
+	// Logger returns the service's structured logger.
+	Logger() *slog.Logger
+	// Tracer returns the service's tracer.
+	Tracer() trace.Tracer
+}
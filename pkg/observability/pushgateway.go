@@ -0,0 +1,23 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// PushMetrics pushes one final scrape of registry to a Prometheus
+// Pushgateway at gatewayURL under jobName, for the window between a
+// service's last scrape and its exit. A blank gatewayURL is a no-op,
+// since most deployments are scraped rather than pushed to.
+func PushMetrics(ctx context.Context, registry *prometheus.Registry, jobName, gatewayURL string) error {
+	if gatewayURL == "" {
+		return nil
+	}
+	return push.New(gatewayURL, jobName).
+		Gatherer(registry).
+		Client(&http.Client{}).
+		PushContext(ctx)
+}
@@ -0,0 +1,47 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceContextHandler wraps a slog.Handler and adds trace_id, span_id, and
+// sampled attributes to every record, pulled from the active span in the
+// record's context. Records emitted outside a span pass through
+// unmodified. Chain it in front of the JSON/Logstash handler so every log
+// line is joinable to the trace that produced it, without each call site
+// plumbing trace fields in by hand.
+type TraceContextHandler struct {
+	next slog.Handler
+}
+
+// NewTraceContextHandler wraps next so records handled through it gain
+// trace correlation attributes.
+func NewTraceContextHandler(next slog.Handler) *TraceContextHandler {
+	return &TraceContextHandler{next: next}
+}
+
+func (h *TraceContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *TraceContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()),
+			slog.Bool("sampled", span.IsSampled()),
+		)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *TraceContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceContextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *TraceContextHandler) WithGroup(name string) slog.Handler {
+	return &TraceContextHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,76 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingHandler captures the last record passed to Handle so tests can
+// inspect the attributes TraceContextHandler added.
+type recordingHandler struct {
+	record slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.record = record
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(name string) slog.Handler       { return h }
+
+func attrMap(record slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+func TestTraceContextHandlerInjectsTraceAttrs(t *testing.T) {
+	next := &recordingHandler{}
+	handler := NewTraceContextHandler(next)
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	if err := handler.Handle(ctx, slog.Record{}); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	attrs := attrMap(next.record)
+	if got := attrs["trace_id"].String(); got != traceID.String() {
+		t.Errorf("trace_id = %q, want %q", got, traceID.String())
+	}
+	if got := attrs["span_id"].String(); got != spanID.String() {
+		t.Errorf("span_id = %q, want %q", got, spanID.String())
+	}
+	if got := attrs["sampled"].Bool(); got != true {
+		t.Errorf("sampled = %v, want true", got)
+	}
+}
+
+func TestTraceContextHandlerPassesThroughWithoutSpan(t *testing.T) {
+	next := &recordingHandler{}
+	handler := NewTraceContextHandler(next)
+
+	if err := handler.Handle(context.Background(), slog.Record{}); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if n := next.record.NumAttrs(); n != 0 {
+		t.Errorf("NumAttrs() = %d, want 0 for a record logged outside a span", n)
+	}
+}
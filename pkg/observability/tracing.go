@@ -0,0 +1,138 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// TracingConfig configures the OTLP exporter and sampling policy used by
+// NewTracerProvider. The exporter and sampling fields can each be
+// overridden by an env var so operators can retune sampling/export without
+// a rebuild; the resource fields cannot.
+type TracingConfig struct {
+	// ServiceName, ServiceVersion, and Environment populate the resource
+	// attributes service.name, service.version, and
+	// deployment.environment on every span.
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+
+	// OTLPProtocol selects the exporter transport: "grpc" (default) or
+	// "http". Overridden by OTEL_EXPORTER_OTLP_PROTOCOL.
+	OTLPProtocol string
+	// OTLPEndpoint is the collector address, e.g. "localhost:4317" for
+	// grpc or "localhost:4318" for http. Overridden by
+	// OTEL_EXPORTER_OTLP_ENDPOINT.
+	OTLPEndpoint string
+
+	// BatchQueueSize bounds the batch span processor's queue. Overridden
+	// by OTEL_BSP_MAX_QUEUE_SIZE. Defaults to sdktrace's own default when
+	// zero.
+	BatchQueueSize int
+	// SamplingRatio is the fraction of traces kept by the ratio-based
+	// sampler, applied to spans without a sampled parent. Overridden by
+	// OTEL_TRACES_SAMPLER_ARG. Defaults to 1 (sample everything) when
+	// zero and unset.
+	SamplingRatio float64
+}
+
+// applyEnv overrides cfg fields from the standard OTel env vars, so
+// operators can retune sampling/export in any environment without a code
+// change.
+func (cfg TracingConfig) applyEnv() TracingConfig {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); v != "" {
+		cfg.OTLPProtocol = v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
+		cfg.OTLPEndpoint = v
+	}
+	if v := os.Getenv("OTEL_BSP_MAX_QUEUE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.BatchQueueSize = n
+		}
+	}
+	if v := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.SamplingRatio = f
+		}
+	}
+	return cfg
+}
+
+// NewTracerProvider builds an OTel TracerProvider that exports spans via
+// OTLP, batched by a BatchSpanProcessor, sampled parent-based with
+// cfg.SamplingRatio as the root sampling ratio. Callers must Shutdown the
+// returned provider during graceful shutdown to flush pending spans.
+func NewTracerProvider(ctx context.Context, cfg TracingConfig) (*sdktrace.TracerProvider, error) {
+	cfg = cfg.applyEnv()
+
+	exporter, err := newOTLPExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("observability: creating OTLP exporter: %w", err)
+	}
+
+	resource, err := sdkresource.Merge(
+		sdkresource.Default(),
+		sdkresource.NewSchemaless(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("observability: building resource: %w", err)
+	}
+
+	ratio := cfg.SamplingRatio
+	if ratio == 0 {
+		if _, err := strconv.ParseFloat(os.Getenv("OTEL_TRACES_SAMPLER_ARG"), 64); err != nil {
+			ratio = 1
+		}
+	}
+
+	var batchOpts []sdktrace.BatchSpanProcessorOption
+	if cfg.BatchQueueSize > 0 {
+		batchOpts = append(batchOpts, sdktrace.WithMaxQueueSize(cfg.BatchQueueSize))
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource),
+		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter, batchOpts...)),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp, nil
+}
+
+func newOTLPExporter(ctx context.Context, cfg TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.OTLPProtocol {
+	case "http":
+		opts := []otlptracehttp.Option{}
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		opts := []otlptracegrpc.Option{}
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	}
+}